@@ -34,25 +34,107 @@
 //
 // Since a Signalman runs each registered function in its own goroutine,
 // it's not necessary to provide a buffered channel for receiving errors.
+//
+// For handlers that need real graceful shutdown — rather than a
+// fire-and-forget goroutine — RegisterCtx and RegisterMapCtx accept a
+// SignalFuncCtx instead, which is given a context carrying the deadline
+// configured via SetShutdownTimeout. When a terminal signal (SIGINT or
+// SIGTERM) is received, a Signalman runs its context-aware handlers off
+// to one side, so that it's still free to notice a second signal (see
+// SetExitOnSignal) while they run. Wait can be used to block until any
+// in-flight handlers, of either kind, have finished.
+//
+// SetExitOnSignal enables "double-tap" behaviour, where a signal
+// arriving twice in quick succession calls os.Exit immediately,
+// bypassing any remaining handlers — useful for users who spam Ctrl+C
+// when cleanup is taking too long. ResetDefault restores the OS's
+// default disposition for a signal and re-raises it, so a process can
+// still exit with the conventional signal exit status once its handlers
+// have run.
+//
+// RegisterNamed registers a single SignalFunc and returns a HandlerID
+// that Unregister and Replace can later use to remove or swap it,
+// without disturbing any other handlers registered against the same
+// signal — useful for long-lived processes that add and remove cleanup
+// hooks dynamically, e.g. per-connection or per-plugin.
+//
+// OnReload, OnToggleDebug and OnDump wrap the common Unix idioms built
+// on top of SIGHUP, SIGUSR1/SIGUSR2 and SIGQUIT respectively. On
+// platforms without these signals, such as Windows, they're no-ops.
+//
+// By default, a Signalman runs every matching handler concurrently, in
+// its own goroutine. SetConcurrency bounds that, per signal, with a
+// dedicated worker pool, for callers who'd rather queue a burst of
+// handlers than run them all at once.
+//
+// Channel and ChannelAll offer an alternative to the callback styles
+// above: they return a channel that receives signals directly, for
+// callers who'd rather select on it than register a SignalFunc. Sends
+// to these channels are non-blocking, exactly like signal.Notify, so a
+// slow subscriber drops a signal instead of stalling delivery to
+// everyone else. StopChannel unsubscribes a channel returned by either
+// method.
 package signalman
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"runtime"
 	"sync"
+	"syscall"
+	"time"
 )
 
 type SignalFunc func() error
 
+// A HandlerID identifies a single handler registered via RegisterNamed,
+// so it can later be removed with Unregister or swapped with Replace.
+// The zero HandlerID is never issued by RegisterNamed, and can be used
+// as a "not found" sentinel.
+type HandlerID uint64
+
+// a handlerEntry pairs a registered SignalFunc with the HandlerID and
+// optional name it was registered under.
+type handlerEntry struct {
+	id   HandlerID
+	name string
+	fn   SignalFunc
+}
+
+// A SignalFuncCtx is a context-aware variant of SignalFunc. The context
+// passed to it carries the deadline configured via SetShutdownTimeout,
+// and is intended to let a handler cut cleanup short if it's taking too
+// long to shut down.
+type SignalFuncCtx func(context.Context) error
+
+// defaultDoubleTapWindow is how soon a repeated signal must arrive,
+// after the first, to be considered a "double-tap" under
+// SetExitOnSignal, unless overridden via SetDoubleTapWindow.
+const defaultDoubleTapWindow = 2 * time.Second
+
 // A Signalman provides methods for registering functions to be executed
 // when the Signalman receives signals.
 type Signalman struct {
-	sc       chan os.Signal
-	ec       chan error
-	handlers map[os.Signal][]SignalFunc
-	started  bool
-	mu       *sync.Mutex
+	sc              chan os.Signal
+	ec              chan error
+	handlers        map[os.Signal][]*handlerEntry
+	ctxHandlers     map[os.Signal][]SignalFuncCtx
+	started         bool
+	mu              *sync.Mutex
+	wg              sync.WaitGroup
+	shutdownTimeout time.Duration
+	sequential      bool
+	exitOnSignal    map[os.Signal]int
+	lastSignalAt    map[os.Signal]time.Time
+	doubleTapWindow time.Duration
+	nextID          HandlerID
+	dumpWriter      io.Writer
+	concurrency     int
+	dispatchers     map[os.Signal]*sigDispatcher
+	chanSubs        map[os.Signal][]chan os.Signal
 }
 
 // By default, the signalman package provides a global Signalman.
@@ -67,9 +149,15 @@ var std = New()
 // Its methods are safe for concurrent access via multiple goroutines.
 func New() *Signalman {
 	return &Signalman{
-		sc:       make(chan os.Signal, 1),
-		handlers: make(map[os.Signal][]SignalFunc),
-		mu:       &sync.Mutex{},
+		sc:           make(chan os.Signal, 1),
+		handlers:     make(map[os.Signal][]*handlerEntry),
+		ctxHandlers:  make(map[os.Signal][]SignalFuncCtx),
+		exitOnSignal: make(map[os.Signal]int),
+		lastSignalAt: make(map[os.Signal]time.Time),
+		mu:           &sync.Mutex{},
+		dumpWriter:   os.Stderr,
+		dispatchers:  make(map[os.Signal]*sigDispatcher),
+		chanSubs:     make(map[os.Signal][]chan os.Signal),
 	}
 }
 
@@ -87,6 +175,95 @@ func SetErrChannel(ec chan error) {
 	std.SetErrChannel(ec)
 }
 
+// SetShutdownTimeout configures how long a Signalman gives its
+// context-aware handlers to run for, when a terminal signal (SIGINT or
+// SIGTERM) is received, before the context passed to them is cancelled.
+//
+// A timeout of zero, the default, means the context is never cancelled
+// by the Signalman, and handlers may take as long as they need.
+func (s *Signalman) SetShutdownTimeout(d time.Duration) {
+	s.mu.Lock()
+	s.shutdownTimeout = d
+	s.mu.Unlock()
+}
+
+// SetShutdownTimeout configures how long the global Signalman gives its
+// context-aware handlers to run for, when a terminal signal (SIGINT or
+// SIGTERM) is received, before the context passed to them is cancelled.
+func SetShutdownTimeout(d time.Duration) {
+	std.SetShutdownTimeout(d)
+}
+
+// SetSequential controls whether a Signalman's context-aware handlers
+// are run one at a time, in registration order, or concurrently, which
+// is the default. Sequential execution is useful when cleanup steps
+// have ordering dependencies on one another.
+func (s *Signalman) SetSequential(sequential bool) {
+	s.mu.Lock()
+	s.sequential = sequential
+	s.mu.Unlock()
+}
+
+// SetSequential controls whether the global Signalman's context-aware
+// handlers are run sequentially, in registration order, or
+// concurrently, which is the default.
+func SetSequential(sequential bool) {
+	std.SetSequential(sequential)
+}
+
+// SetExitOnSignal enables "double-tap" behaviour for sig: the first
+// occurrence is handled as normal, but a second sig arriving within the
+// configured double-tap window (see SetDoubleTapWindow) causes the
+// Signalman to call os.Exit(code) immediately, bypassing any remaining
+// handlers.
+//
+// This mirrors the common CLI/server convention that a user who sends
+// an interrupt twice in quick succession wants to skip a hung cleanup.
+func (s *Signalman) SetExitOnSignal(sig os.Signal, code int) {
+	s.mu.Lock()
+	s.exitOnSignal[sig] = code
+	s.mu.Unlock()
+}
+
+// SetExitOnSignal enables "double-tap" behaviour for sig on the global
+// Signalman. See (*Signalman).SetExitOnSignal for details.
+func SetExitOnSignal(sig os.Signal, code int) {
+	std.SetExitOnSignal(sig, code)
+}
+
+// SetDoubleTapWindow configures how soon a repeated signal must arrive,
+// after the first, to be treated as a "double-tap" by SetExitOnSignal.
+// The default is two seconds.
+func (s *Signalman) SetDoubleTapWindow(d time.Duration) {
+	s.mu.Lock()
+	s.doubleTapWindow = d
+	s.mu.Unlock()
+}
+
+// SetDoubleTapWindow configures the double-tap window on the global
+// Signalman. See (*Signalman).SetDoubleTapWindow for details.
+func SetDoubleTapWindow(d time.Duration) {
+	std.SetDoubleTapWindow(d)
+}
+
+// ResetDefault restores the OS's default disposition for sig, undoing
+// any interception installed via Register, RegisterCtx or similar, and
+// then re-raises sig against the current process.
+//
+// It's intended to be called once a Signalman's handlers for sig have
+// finished running — typically from within one of those handlers, or
+// immediately after Wait returns — so that the process goes on to exit
+// with the conventional exit status for the signal (128+signum on
+// Unix), rather than whatever status the program would otherwise use.
+func ResetDefault(sig os.Signal) {
+	signal.Reset(sig)
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return
+	}
+	p.Signal(sig)
+}
+
 func notify(sig os.Signal, sc chan os.Signal) {
 	if sig == nil {
 		// all signals are to be sent on channel
@@ -96,21 +273,54 @@ func notify(sig os.Signal, sc chan os.Signal) {
 	}
 }
 
+// isTerminalSignal reports whether sig is one of the signals that
+// conventionally instructs a process to shut down.
+func isTerminalSignal(sig os.Signal) bool {
+	return sig == syscall.SIGINT || sig == syscall.SIGTERM
+}
+
+// nextHandlerID returns a new, unique HandlerID. Callers must hold s.mu.
+func (s *Signalman) nextHandlerID() HandlerID {
+	s.nextID++
+	return s.nextID
+}
+
+// resubscribeLocked stops relaying any signals to s.sc and re-notifies
+// it only for the signals that currently have at least one handler or
+// channel subscriber. It's used to hand default disposition for a
+// signal back to the OS once its last handler has been removed via
+// Unregister, or its last channel subscriber via StopChannel. Callers
+// must hold s.mu.
+func (s *Signalman) resubscribeLocked() {
+	signal.Stop(s.sc)
+	for sig := range s.handlers {
+		notify(sig, s.sc)
+	}
+	for sig := range s.ctxHandlers {
+		notify(sig, s.sc)
+	}
+	for sig := range s.chanSubs {
+		notify(sig, s.sc)
+	}
+}
+
 // Register one or more SignalFuncs against an os.Signal.
 //
 // If sig is nil, all signals received by the Signalman will result in
 // the provided SignalFuncs being executed.
 func (s *Signalman) Register(sig os.Signal, fun ...SignalFunc) {
-	notify(sig, s.sc)
 	s.mu.Lock()
+	entries := make([]*handlerEntry, len(fun))
+	for i, f := range fun {
+		entries[i] = &handlerEntry{id: s.nextHandlerID(), fn: f}
+	}
 	h, ok := s.handlers[sig]
 	if !ok {
-		s.handlers[sig] = fun
+		s.handlers[sig] = entries
 	} else {
-		for _, f := range fun {
-			s.handlers[sig] = append(h, f)
-		}
+		s.handlers[sig] = append(h, entries...)
 	}
+	notify(sig, s.sc)
 	s.mu.Unlock()
 }
 
@@ -122,6 +332,142 @@ func Register(sig os.Signal, fun ...SignalFunc) {
 	std.Register(sig, fun...)
 }
 
+// RegisterNamed registers a single SignalFunc against sig, identified
+// by name, and returns a HandlerID that Unregister or Replace can later
+// use to manage it. name may be empty if the caller has no need to
+// Unregister or Replace it later.
+//
+// If sig is nil, the handler is executed for all signals received by
+// the Signalman. RegisterNamed reports an error if name is non-empty
+// and already in use by another handler registered against sig.
+func (s *Signalman) RegisterNamed(sig os.Signal, name string, fn SignalFunc) (HandlerID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if name != "" {
+		for _, e := range s.handlers[sig] {
+			if e.name == name {
+				return 0, fmt.Errorf("handler named %q already registered for signal %v", name, sig)
+			}
+		}
+	}
+
+	id := s.nextHandlerID()
+	s.handlers[sig] = append(s.handlers[sig], &handlerEntry{id: id, name: name, fn: fn})
+	notify(sig, s.sc)
+	return id, nil
+}
+
+// RegisterNamed registers a single SignalFunc against sig on the global
+// Signalman. See (*Signalman).RegisterNamed for details.
+func RegisterNamed(sig os.Signal, name string, fn SignalFunc) (HandlerID, error) {
+	return std.RegisterNamed(sig, name, fn)
+}
+
+// Unregister removes the handler identified by id. If it was the last
+// handler registered for its signal, the Signalman stops listening for
+// that signal, returning it to its default OS disposition.
+func (s *Signalman) Unregister(id HandlerID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sig, entries := range s.handlers {
+		for i, e := range entries {
+			if e.id != id {
+				continue
+			}
+			remaining := append(entries[:i:i], entries[i+1:]...)
+			if len(remaining) == 0 {
+				delete(s.handlers, sig)
+				s.resubscribeLocked()
+			} else {
+				s.handlers[sig] = remaining
+			}
+			return
+		}
+	}
+}
+
+// Unregister removes the handler identified by id from the global
+// Signalman. See (*Signalman).Unregister for details.
+func Unregister(id HandlerID) {
+	std.Unregister(id)
+}
+
+// Replace swaps the SignalFunc behind the handler identified by id for
+// fn, preserving its position, signal and name. It reports whether a
+// handler with that id was found.
+func (s *Signalman) Replace(id HandlerID, fn SignalFunc) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entries := range s.handlers {
+		for _, e := range entries {
+			if e.id == id {
+				e.fn = fn
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Replace swaps the SignalFunc behind the handler identified by id on
+// the global Signalman. See (*Signalman).Replace for details.
+func Replace(id HandlerID, fn SignalFunc) bool {
+	return std.Replace(id, fn)
+}
+
+// Handlers returns the HandlerIDs currently registered against sig, in
+// registration order.
+func (s *Signalman) Handlers(sig os.Signal) []HandlerID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := s.handlers[sig]
+	ids := make([]HandlerID, len(entries))
+	for i, e := range entries {
+		ids[i] = e.id
+	}
+	return ids
+}
+
+// Handlers returns the HandlerIDs currently registered against sig on
+// the global Signalman, in registration order.
+func Handlers(sig os.Signal) []HandlerID {
+	return std.Handlers(sig)
+}
+
+// RegisterCtx registers one or more SignalFuncCtx against an os.Signal.
+//
+// If sig is nil, all signals received by the Signalman will result in
+// the provided SignalFuncCtx being executed. When sig is a terminal
+// signal (SIGINT or SIGTERM), the Signalman runs these handlers with a
+// context governed by SetShutdownTimeout. They run off the dispatch
+// loop, so the Signalman remains free to notice a second signal (see
+// SetExitOnSignal) while they're still running; use Wait to block until
+// they've returned.
+func (s *Signalman) RegisterCtx(sig os.Signal, fun ...SignalFuncCtx) {
+	s.mu.Lock()
+	h, ok := s.ctxHandlers[sig]
+	if !ok {
+		s.ctxHandlers[sig] = fun
+	} else {
+		s.ctxHandlers[sig] = append(h, fun...)
+	}
+	notify(sig, s.sc)
+	s.mu.Unlock()
+}
+
+// RegisterCtx registers one or more SignalFuncCtx against an os.Signal,
+// on the global Signalman.
+//
+// If sig is nil, all signals received by the global Signalman will
+// result in the provided SignalFuncCtx being executed.
+func RegisterCtx(sig os.Signal, fun ...SignalFuncCtx) {
+	std.RegisterCtx(sig, fun...)
+}
+
 // RegisterMap registers multiple SignalFuncs against signals, on the
 // Signalman.
 //
@@ -131,13 +477,15 @@ func (s *Signalman) RegisterMap(signals map[os.Signal][]SignalFunc) {
 	s.mu.Lock()
 	for sig, handlers := range signals {
 		notify(sig, s.sc)
+		entries := make([]*handlerEntry, len(handlers))
+		for i, f := range handlers {
+			entries[i] = &handlerEntry{id: s.nextHandlerID(), fn: f}
+		}
 		h, ok := s.handlers[sig]
 		if !ok {
-			s.handlers[sig] = handlers
+			s.handlers[sig] = entries
 		} else {
-			for _, handler := range handlers {
-				s.handlers[sig] = append(h, handler)
-			}
+			s.handlers[sig] = append(h, entries...)
 		}
 	}
 	s.mu.Unlock()
@@ -151,20 +499,370 @@ func RegisterMap(signals map[os.Signal][]SignalFunc) {
 	std.RegisterMap(signals)
 }
 
-func (s *Signalman) handleSignal(sig os.Signal) {
-	funcs, ok := s.handlers[sig]
-	if !ok && s.ec != nil {
-		s.ec <- fmt.Errorf("Signal %v has no registered handlers.", sig)
+// RegisterMapCtx registers multiple SignalFuncCtx against signals, on
+// the Signalman.
+//
+// A nil key will result in the Signalman executing the functions
+// associated with the nil key, for all received signals.
+func (s *Signalman) RegisterMapCtx(signals map[os.Signal][]SignalFuncCtx) {
+	s.mu.Lock()
+	for sig, handlers := range signals {
+		notify(sig, s.sc)
+		h, ok := s.ctxHandlers[sig]
+		if !ok {
+			s.ctxHandlers[sig] = handlers
+		} else {
+			s.ctxHandlers[sig] = append(h, handlers...)
+		}
+	}
+	s.mu.Unlock()
+}
+
+// RegisterMapCtx registers multiple SignalFuncCtx against signals, on
+// the global Signalman.
+//
+// A nil key will result in the global Signalman executing the functions
+// associated with the nil key, for all received signals.
+func RegisterMapCtx(signals map[os.Signal][]SignalFuncCtx) {
+	std.RegisterMapCtx(signals)
+}
+
+// Channel returns a receive-only channel that sig is sent on, mirroring
+// the style of signal.Notify. Sends to it are non-blocking: if its
+// buffer is full, the signal is dropped for that subscriber rather than
+// stalling delivery to anyone else — the same behaviour signal.Notify
+// documents. Use StopChannel to unsubscribe it once it's no longer
+// needed.
+//
+// If sig is nil, the channel receives every signal the Signalman is
+// otherwise watching for; ChannelAll is a shorthand for this.
+func (s *Signalman) Channel(sig os.Signal, buf int) <-chan os.Signal {
+	ch := make(chan os.Signal, buf)
+	s.mu.Lock()
+	s.chanSubs[sig] = append(s.chanSubs[sig], ch)
+	notify(sig, s.sc)
+	s.mu.Unlock()
+	return ch
+}
+
+// Channel returns a channel on the global Signalman that receives sig.
+// See (*Signalman).Channel for details.
+func Channel(sig os.Signal, buf int) <-chan os.Signal {
+	return std.Channel(sig, buf)
+}
+
+// ChannelAll returns a receive-only channel that every signal the
+// Signalman is notified of is sent on. It's equivalent to
+// Channel(nil, buf).
+func (s *Signalman) ChannelAll(buf int) <-chan os.Signal {
+	return s.Channel(nil, buf)
+}
+
+// ChannelAll returns a channel on the global Signalman that receives
+// every signal it's notified of. See (*Signalman).ChannelAll for
+// details.
+func ChannelAll(buf int) <-chan os.Signal {
+	return std.ChannelAll(buf)
+}
+
+// StopChannel unsubscribes ch, previously returned by Channel or
+// ChannelAll, so it stops receiving signals. It does not close ch. If
+// ch isn't currently subscribed, StopChannel is a no-op.
+func (s *Signalman) StopChannel(ch <-chan os.Signal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sig, subs := range s.chanSubs {
+		for i, c := range subs {
+			if c != ch {
+				continue
+			}
+			remaining := append(subs[:i:i], subs[i+1:]...)
+			if len(remaining) == 0 {
+				delete(s.chanSubs, sig)
+				s.resubscribeLocked()
+			} else {
+				s.chanSubs[sig] = remaining
+			}
+			return
+		}
+	}
+}
+
+// StopChannel unsubscribes ch from the global Signalman. See
+// (*Signalman).StopChannel for details.
+func StopChannel(ch <-chan os.Signal) {
+	std.StopChannel(ch)
+}
+
+// SetDumpWriter configures where OnDump writes goroutine stacks to.
+// The default is os.Stderr.
+func (s *Signalman) SetDumpWriter(w io.Writer) {
+	s.mu.Lock()
+	s.dumpWriter = w
+	s.mu.Unlock()
+}
+
+// SetDumpWriter configures where the global Signalman's OnDump writes
+// goroutine stacks to. The default is os.Stderr.
+func SetDumpWriter(w io.Writer) {
+	std.SetDumpWriter(w)
+}
+
+// OnReload registers fn to run when the Signalman receives SIGHUP, the
+// conventional Unix signal asking a long-running process to reload its
+// configuration. On platforms without SIGHUP, such as Windows, this is
+// a no-op.
+func (s *Signalman) OnReload(fn SignalFunc) {
+	s.Register(sigHUP, fn)
+}
+
+// OnReload registers fn to run when the global Signalman receives
+// SIGHUP. See (*Signalman).OnReload for details.
+func OnReload(fn SignalFunc) {
+	std.OnReload(fn)
+}
+
+// OnToggleDebug registers fn to be called with true when the Signalman
+// receives SIGUSR1, and with false when it receives SIGUSR2 —
+// conventional Unix signals for flipping a process's debug/verbose
+// state at runtime without restarting it. On platforms without these
+// signals, such as Windows, this is a no-op.
+func (s *Signalman) OnToggleDebug(fn func(enabled bool)) {
+	s.Register(sigUSR1, func() error { fn(true); return nil })
+	s.Register(sigUSR2, func() error { fn(false); return nil })
+}
+
+// OnToggleDebug registers fn on the global Signalman. See
+// (*Signalman).OnToggleDebug for details.
+func OnToggleDebug(fn func(enabled bool)) {
+	std.OnToggleDebug(fn)
+}
+
+// OnDump registers fn to run when the Signalman receives SIGQUIT, the
+// conventional Unix signal for dumping diagnostic state before a
+// process exits. After fn returns, the Signalman writes the stacks of
+// every running goroutine, via runtime.Stack, to the writer configured
+// with SetDumpWriter. On platforms without SIGQUIT, such as Windows,
+// this is a no-op.
+func (s *Signalman) OnDump(fn SignalFunc) {
+	s.Register(sigQUIT, func() error {
+		err := fn()
+		s.writeStacks()
+		return err
+	})
+}
+
+// OnDump registers fn on the global Signalman. See (*Signalman).OnDump
+// for details.
+func OnDump(fn SignalFunc) {
+	std.OnDump(fn)
+}
+
+// writeStacks writes the stacks of every running goroutine to the
+// configured dump writer, growing its buffer until the dump fits.
+func (s *Signalman) writeStacks() {
+	s.mu.Lock()
+	w := s.dumpWriter
+	s.mu.Unlock()
+	if w == nil {
 		return
 	}
 
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			w.Write(buf[:n])
+			return
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// runCtxHandlers invokes funcs with ctx, either sequentially, in
+// registration order, or concurrently, depending on sequential, sending
+// any errors on ec. It returns once every handler has returned.
+func (s *Signalman) runCtxHandlers(ctx context.Context, funcs []SignalFuncCtx, ec chan error, sequential bool) {
+	if sequential {
+		for _, f := range funcs {
+			if err := f(ctx); err != nil && ec != nil {
+				ec <- err
+			}
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
 	for _, f := range funcs {
+		wg.Add(1)
+		go func(f SignalFuncCtx) {
+			defer wg.Done()
+			if err := f(ctx); err != nil && ec != nil {
+				ec <- err
+			}
+		}(f)
+	}
+	wg.Wait()
+}
+
+// a sigDispatcher runs jobs submitted for a single signal across a
+// bounded pool of worker goroutines, so a burst of handlers for one
+// signal can't spawn unbounded concurrency.
+type sigDispatcher struct {
+	jobs chan func()
+}
+
+func newSigDispatcher(workers int) *sigDispatcher {
+	d := &sigDispatcher{jobs: make(chan func())}
+	for i := 0; i < workers; i++ {
 		go func() {
-			if err := f(); err != nil && s.ec != nil {
-				s.ec <- err
+			for job := range d.jobs {
+				job()
 			}
 		}()
 	}
+	return d
+}
+
+func (d *sigDispatcher) submit(job func()) { d.jobs <- job }
+func (d *sigDispatcher) stop()             { close(d.jobs) }
+
+// SetConcurrency bounds how many SignalFuncs may run concurrently for a
+// given signal, dispatching through a dedicated worker pool per signal.
+//
+// A value of zero or less, the default, leaves handlers unbounded: each
+// one runs in its own goroutine, as documented.
+func (s *Signalman) SetConcurrency(n int) {
+	s.mu.Lock()
+	s.concurrency = n
+	s.mu.Unlock()
+}
+
+// SetConcurrency bounds concurrency on the global Signalman. See
+// (*Signalman).SetConcurrency for details.
+func SetConcurrency(n int) {
+	std.SetConcurrency(n)
+}
+
+// dispatcherFor returns the worker pool dispatcher for sig, creating it
+// if necessary, or nil if SetConcurrency hasn't been used.
+func (s *Signalman) dispatcherFor(sig os.Signal) *sigDispatcher {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.concurrency <= 0 {
+		return nil
+	}
+	d, ok := s.dispatchers[sig]
+	if !ok {
+		d = newSigDispatcher(s.concurrency)
+		s.dispatchers[sig] = d
+	}
+	return d
+}
+
+func (s *Signalman) handleSignal(sig os.Signal) {
+	s.mu.Lock()
+
+	code, exitOK := s.exitOnSignal[sig]
+	if exitOK {
+		window := s.doubleTapWindow
+		if window <= 0 {
+			window = defaultDoubleTapWindow
+		}
+		now := time.Now()
+		if last, seen := s.lastSignalAt[sig]; seen && now.Sub(last) <= window {
+			s.mu.Unlock()
+			os.Exit(code)
+		}
+		s.lastSignalAt[sig] = now
+	}
+
+	// Snapshot everything handleSignal needs while holding the lock, so
+	// dispatch below never touches Signalman state without it.
+	ec := s.ec
+	shutdownTimeout := s.shutdownTimeout
+	sequential := s.sequential
+	entries, hasFuncs := s.handlers[sig]
+	fns := make([]SignalFunc, len(entries))
+	for i, e := range entries {
+		fns[i] = e.fn
+	}
+	ctxEntries, hasCtxFuncs := s.ctxHandlers[sig]
+	ctxFuncs := append([]SignalFuncCtx(nil), ctxEntries...)
+	chanSubs := append([]chan os.Signal(nil), s.chanSubs[sig]...)
+	chanSubs = append(chanSubs, s.chanSubs[nil]...)
+
+	s.mu.Unlock()
+
+	if !hasFuncs && !hasCtxFuncs && len(chanSubs) == 0 {
+		if ec != nil {
+			ec <- fmt.Errorf("Signal %v has no registered handlers.", sig)
+		}
+		return
+	}
+
+	// Fan out to channel subscribers the same way signal.Notify does:
+	// a non-blocking send, dropping the signal for any subscriber
+	// that isn't ready to receive it. Reporting the drop on ec is
+	// non-blocking too, so a caller that isn't draining ec can't freeze
+	// this dispatch loop any more than a slow channel subscriber can.
+	for _, ch := range chanSubs {
+		select {
+		case ch <- sig:
+		default:
+			if ec != nil {
+				select {
+				case ec <- fmt.Errorf("Signal %v dropped for a slow channel subscriber.", sig):
+				default:
+				}
+			}
+		}
+	}
+
+	for _, fn := range fns {
+		fn := fn
+		s.wg.Add(1)
+		job := func() {
+			defer s.wg.Done()
+			if err := fn(); err != nil && ec != nil {
+				ec <- err
+			}
+		}
+		if d := s.dispatcherFor(sig); d != nil {
+			// Submitting can block until a worker is free, so it must
+			// never happen on the dispatch loop itself — otherwise a
+			// saturated pool would stall delivery of every signal.
+			go d.submit(job)
+		} else {
+			go job()
+		}
+	}
+
+	if !hasCtxFuncs {
+		return
+	}
+
+	// Context-aware handlers always run off the dispatch loop, even for
+	// terminal signals: blocking this goroutine on a hung (or merely
+	// slow) cleanup would also block it from ever reading a second
+	// SIGINT/SIGTERM off s.sc, defeating SetExitOnSignal's double-tap
+	// escape hatch. Wait still blocks until they're done; a terminal
+	// signal's handlers additionally get the deadline from
+	// SetShutdownTimeout.
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ctx := context.Background()
+		if isTerminalSignal(sig) && shutdownTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, shutdownTimeout)
+			defer cancel()
+		}
+		s.runCtxHandlers(ctx, ctxFuncs, ec, sequential)
+	}()
 }
 
 // Start instructs the Signalman to begin listening for incoming signals.
@@ -186,20 +884,50 @@ func Start() {
 	std.Start()
 }
 
+// Wait blocks until every in-flight handler — whether a SignalFunc or a
+// SignalFuncCtx — has returned.
+//
+// It's typically called after a terminal signal has been received, so
+// that a process doesn't exit before its cleanup handlers have finished.
+func (s *Signalman) Wait() {
+	s.wg.Wait()
+}
+
+// Wait blocks until every in-flight handler registered on the global
+// Signalman has returned.
+func Wait() {
+	std.Wait()
+}
+
 // Stop instructs the Signalman to stop listening for incoming signals.
 //
-// When Stop is called, all mapped SignalFuncs are removed. If Start is
-// called in the future, SignalFuncs will need to be registered again
-// before they're executed.
+// When Stop is called, all mapped SignalFuncs are removed and any
+// channels returned by Channel or ChannelAll are unsubscribed (though
+// not closed). If Start is called in the future, SignalFuncs will need
+// to be registered again before they're executed. Stop blocks until any
+// handlers already in flight have returned before releasing their
+// worker pools.
 func (s *Signalman) Stop() {
 	// Stop all signals being sent on channel.
 	signal.Stop(s.sc)
 	// Destroy handler mapping since signals are no longer registered.
 	s.mu.Lock()
-	s.handlers = make(map[os.Signal][]SignalFunc)
+	s.handlers = make(map[os.Signal][]*handlerEntry)
+	s.ctxHandlers = make(map[os.Signal][]SignalFuncCtx)
+	s.lastSignalAt = make(map[os.Signal]time.Time)
+	s.chanSubs = make(map[os.Signal][]chan os.Signal)
+	dispatchers := s.dispatchers
+	s.dispatchers = make(map[os.Signal]*sigDispatcher)
 	s.mu.Unlock()
 	// Close the channel
 	close(s.sc)
+
+	// Drain any handlers still in flight before tearing down their
+	// worker pools.
+	s.wg.Wait()
+	for _, d := range dispatchers {
+		d.stop()
+	}
 }
 
 // Stop instructs the global Signalman to stop listening for incoming signals.
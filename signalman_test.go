@@ -1,8 +1,15 @@
 package signalman
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -49,18 +56,15 @@ func Test_handleSignal(t *testing.T) {
 	}
 
 	// register signal and handlers on signalman
-	i := 0
-	f := func() error { i++; return nil }
-	g := func() error { i++; return fmt.Errorf("error function") }
+	var i int32
+	f := func() error { atomic.AddInt32(&i, 1); return nil }
+	g := func() error { atomic.AddInt32(&i, 1); return fmt.Errorf("error function") }
 	sm.Register(os.Interrupt, f, g)
 
 	// Second function should return an error, which is sent on the
 	// error channel.
 	sm.handleSignal(os.Interrupt)
-	// Not pleasant, but much less code than a select with timeout.
-	// Needed since the test check will complete before the error is
-	// sent on the channel.
-	time.Sleep(time.Millisecond * 5)
+	sm.Wait()
 
 	if len(ec) != 1 {
 		t.Fatal("Wrong number of errors received on channel")
@@ -70,3 +74,536 @@ func Test_handleSignal(t *testing.T) {
 		t.Fatalf("expected %v\ngot %v", 2, i)
 	}
 }
+
+func Test_RegisterCtx(t *testing.T) {
+	sm := New()
+
+	f := func(context.Context) error { return nil }
+
+	sm.RegisterCtx(os.Interrupt, f)
+	actual := sm.ctxHandlers[os.Interrupt]
+	if len(actual) != 1 {
+		t.Fatalf("expected %v\ngot %v", 1, len(actual))
+	}
+}
+
+func Test_handleSignal_terminal_waits_for_ctx_handlers(t *testing.T) {
+	sm := New()
+	sm.SetShutdownTimeout(time.Second)
+
+	done := false
+	f := func(ctx context.Context) error {
+		time.Sleep(time.Millisecond * 5)
+		done = true
+		return nil
+	}
+	sm.RegisterCtx(syscall.SIGTERM, f)
+
+	// Context-aware handlers run off the dispatch loop (so a hung one
+	// can't block SetExitOnSignal's double-tap detection); Wait is what
+	// blocks until they've returned.
+	sm.handleSignal(syscall.SIGTERM)
+	sm.Wait()
+
+	if !done {
+		t.Fatal("Wait returned before terminal ctx handler finished")
+	}
+}
+
+func Test_handleSignal_terminal_ctx_deadline(t *testing.T) {
+	sm := New()
+	sm.SetShutdownTimeout(time.Millisecond)
+
+	var ctxErr error
+	f := func(ctx context.Context) error {
+		<-ctx.Done()
+		ctxErr = ctx.Err()
+		return nil
+	}
+	sm.RegisterCtx(syscall.SIGINT, f)
+
+	sm.handleSignal(syscall.SIGINT)
+	sm.Wait()
+
+	if ctxErr != context.DeadlineExceeded {
+		t.Fatalf("expected %v\ngot %v", context.DeadlineExceeded, ctxErr)
+	}
+}
+
+// TestHelperDoubleTapExitDuringHungCtxHandler is not a real test; it's
+// invoked as a subprocess by
+// Test_handleSignal_terminal_doubleTap_notBlockedByCtxHandler to exercise
+// the os.Exit call, which can't be observed in-process.
+func TestHelperDoubleTapExitDuringHungCtxHandler(t *testing.T) {
+	if os.Getenv("SIGNALMAN_DOUBLE_TAP_HUNG_CTX_HELPER") != "1" {
+		t.Skip("not running as double-tap helper process")
+	}
+
+	sm := New()
+	sm.SetExitOnSignal(syscall.SIGINT, 42)
+	sm.RegisterCtx(syscall.SIGINT, func(ctx context.Context) error {
+		select {} // hang forever, simulating a stuck cleanup
+	})
+
+	sm.handleSignal(syscall.SIGINT) // first tap, starts the hung ctx handler
+	sm.handleSignal(syscall.SIGINT) // second tap, should os.Exit regardless
+	os.Exit(1)                      // should be unreachable
+}
+
+// Test_handleSignal_terminal_doubleTap_notBlockedByCtxHandler is the
+// regression test for the bug SetExitOnSignal was meant to dodge: a
+// second terminal signal must still force-exit even while a first
+// signal's ctx handler is hung, because ctx handlers no longer block
+// the dispatch loop.
+func Test_handleSignal_terminal_doubleTap_notBlockedByCtxHandler(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=TestHelperDoubleTapExitDuringHungCtxHandler")
+	cmd.Env = append(os.Environ(), "SIGNALMAN_DOUBLE_TAP_HUNG_CTX_HELPER=1")
+	err := cmd.Run()
+
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatalf("expected helper process to exit with an error, got %v", err)
+	}
+	if code := exitErr.ExitCode(); code != 42 {
+		t.Fatalf("expected exit code %v\ngot %v", 42, code)
+	}
+}
+
+func Test_Wait(t *testing.T) {
+	sm := New()
+
+	i := 0
+	f := func() error {
+		time.Sleep(time.Millisecond * 5)
+		i++
+		return nil
+	}
+	sm.Register(os.Interrupt, f)
+
+	sm.handleSignal(os.Interrupt)
+	sm.Wait()
+
+	if i != 1 {
+		t.Fatalf("expected %v\ngot %v", 1, i)
+	}
+}
+
+func Test_SetExitOnSignal(t *testing.T) {
+	sm := New()
+	sm.SetExitOnSignal(os.Interrupt, 3)
+
+	code, ok := sm.exitOnSignal[os.Interrupt]
+	if !ok || code != 3 {
+		t.Fatalf("expected %v, %v\ngot %v, %v", 3, true, code, ok)
+	}
+}
+
+func Test_handleSignal_doubleTap_outsideWindow(t *testing.T) {
+	sm := New()
+	sm.SetDoubleTapWindow(time.Millisecond)
+	sm.SetExitOnSignal(os.Interrupt, 1)
+
+	i := 0
+	sm.Register(os.Interrupt, func() error { i++; return nil })
+
+	sm.handleSignal(os.Interrupt)
+	sm.Wait()
+	time.Sleep(time.Millisecond * 5)
+	sm.handleSignal(os.Interrupt)
+	sm.Wait()
+
+	if i != 2 {
+		t.Fatalf("expected %v\ngot %v", 2, i)
+	}
+}
+
+// TestHelperDoubleTapExit is not a real test; it's invoked as a
+// subprocess by Test_handleSignal_doubleTap_exits to exercise the
+// os.Exit call, which can't be observed in-process.
+func TestHelperDoubleTapExit(t *testing.T) {
+	if os.Getenv("SIGNALMAN_DOUBLE_TAP_HELPER") != "1" {
+		t.Skip("not running as double-tap helper process")
+	}
+
+	sm := New()
+	sm.SetExitOnSignal(os.Interrupt, 42)
+	sm.handleSignal(os.Interrupt)
+	sm.handleSignal(os.Interrupt)
+	os.Exit(1) // should be unreachable
+}
+
+func Test_RegisterNamed(t *testing.T) {
+	sm := New()
+
+	f := func() error { return nil }
+	id, err := sm.RegisterNamed(os.Interrupt, "cleanup", f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id == 0 {
+		t.Fatal("expected a non-zero HandlerID")
+	}
+
+	if _, err := sm.RegisterNamed(os.Interrupt, "cleanup", f); err == nil {
+		t.Fatal("expected an error registering a duplicate name")
+	}
+}
+
+func Test_Unregister(t *testing.T) {
+	sm := New()
+
+	f := func() error { return nil }
+	id1, _ := sm.RegisterNamed(os.Interrupt, "first", f)
+	id2, _ := sm.RegisterNamed(os.Interrupt, "second", f)
+
+	sm.Unregister(id1)
+	ids := sm.Handlers(os.Interrupt)
+	if len(ids) != 1 || ids[0] != id2 {
+		t.Fatalf("expected %v\ngot %v", []HandlerID{id2}, ids)
+	}
+
+	sm.Unregister(id2)
+	if ids := sm.Handlers(os.Interrupt); len(ids) != 0 {
+		t.Fatalf("expected no handlers left\ngot %v", ids)
+	}
+	if _, ok := sm.handlers[os.Interrupt]; ok {
+		t.Fatal("expected signal entry to be removed once its last handler was unregistered")
+	}
+}
+
+func Test_Replace(t *testing.T) {
+	sm := New()
+
+	i := 0
+	id, _ := sm.RegisterNamed(os.Interrupt, "counter", func() error { i++; return nil })
+
+	j := 0
+	if ok := sm.Replace(id, func() error { j++; return nil }); !ok {
+		t.Fatal("expected Replace to find the handler")
+	}
+
+	sm.handleSignal(os.Interrupt)
+	sm.Wait()
+
+	if i != 0 || j != 1 {
+		t.Fatalf("expected i=0, j=1\ngot i=%v, j=%v", i, j)
+	}
+
+	if ok := sm.Replace(HandlerID(9999), func() error { return nil }); ok {
+		t.Fatal("expected Replace to report no handler found for an unknown id")
+	}
+}
+
+func Test_OnReload(t *testing.T) {
+	sm := New()
+
+	reloaded := false
+	sm.OnReload(func() error { reloaded = true; return nil })
+
+	sm.handleSignal(sigHUP)
+	sm.Wait()
+
+	if !reloaded {
+		t.Fatal("expected OnReload handler to have run")
+	}
+}
+
+func Test_OnToggleDebug(t *testing.T) {
+	sm := New()
+
+	var enabled bool
+	sm.OnToggleDebug(func(e bool) { enabled = e })
+
+	sm.handleSignal(sigUSR1)
+	sm.Wait()
+	if !enabled {
+		t.Fatal("expected SIGUSR1 to toggle debug on")
+	}
+
+	sm.handleSignal(sigUSR2)
+	sm.Wait()
+	if enabled {
+		t.Fatal("expected SIGUSR2 to toggle debug off")
+	}
+}
+
+func Test_OnDump(t *testing.T) {
+	sm := New()
+
+	var buf bytes.Buffer
+	sm.SetDumpWriter(&buf)
+
+	dumped := false
+	sm.OnDump(func() error { dumped = true; return nil })
+
+	sm.handleSignal(sigQUIT)
+	sm.Wait()
+
+	if !dumped {
+		t.Fatal("expected OnDump handler to have run")
+	}
+	if !strings.Contains(buf.String(), "goroutine") {
+		t.Fatalf("expected a goroutine dump, got %q", buf.String())
+	}
+}
+
+func Test_handleSignal_doubleTap_exits(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=TestHelperDoubleTapExit")
+	cmd.Env = append(os.Environ(), "SIGNALMAN_DOUBLE_TAP_HELPER=1")
+	err := cmd.Run()
+
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatalf("expected helper process to exit with an error, got %v", err)
+	}
+	if code := exitErr.ExitCode(); code != 42 {
+		t.Fatalf("expected exit code %v\ngot %v", 42, code)
+	}
+}
+
+// Test_concurrent_Register_Stop_handleSignal exercises Register,
+// SetErrChannel, handleSignal and Stop concurrently from multiple
+// goroutines, in the style of the race fixed in Go's own os/signal
+// package around Stop racing with delivery. It doesn't assert much
+// beyond "the race detector has nothing to say".
+func Test_concurrent_Register_Stop_handleSignal(t *testing.T) {
+	sm := New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(3)
+
+		go func() {
+			defer wg.Done()
+			sm.Register(os.Interrupt, func() error { return nil })
+		}()
+
+		go func() {
+			defer wg.Done()
+			sm.SetErrChannel(make(chan error, 1))
+		}()
+
+		go func() {
+			defer wg.Done()
+			sm.handleSignal(os.Interrupt)
+		}()
+	}
+	wg.Wait()
+	sm.Wait()
+
+	sm.Stop()
+}
+
+// Test_concurrent_SetSequential_handleSignal exercises SetSequential
+// racing with a terminal signal's ctx-handler dispatch, which reads it
+// from a goroutine off the main dispatch loop. It doesn't assert much
+// beyond "the race detector has nothing to say".
+func Test_concurrent_SetSequential_handleSignal(t *testing.T) {
+	sm := New()
+	sm.SetShutdownTimeout(time.Second)
+	sm.RegisterCtx(syscall.SIGTERM, func(context.Context) error { return nil })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+			sm.SetSequential(i%2 == 0)
+		}(i)
+
+		go func() {
+			defer wg.Done()
+			sm.handleSignal(syscall.SIGTERM)
+		}()
+	}
+	wg.Wait()
+	sm.Wait()
+}
+
+// Test_concurrent_Register_resubscribe exercises Register, RegisterCtx,
+// Channel and Unregister concurrently. Those registration methods used
+// to call notify before taking s.mu, so a concurrent Unregister's
+// resubscribeLocked could interleave in the gap and rebuild OS signal
+// relay from the handler maps before the new registration was inserted,
+// silently dropping it. It doesn't assert much beyond "the race detector
+// has nothing to say" and that registration bookkeeping stays
+// consistent under the churn.
+func Test_concurrent_Register_resubscribe(t *testing.T) {
+	sm := New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(4)
+
+		go func() {
+			defer wg.Done()
+			sm.Register(syscall.SIGUSR1, func() error { return nil })
+		}()
+
+		go func() {
+			defer wg.Done()
+			sm.RegisterCtx(syscall.SIGUSR1, func(context.Context) error { return nil })
+		}()
+
+		go func() {
+			defer wg.Done()
+			ch := sm.Channel(syscall.SIGUSR1, 1)
+			sm.StopChannel(ch)
+		}()
+
+		go func() {
+			defer wg.Done()
+			id, _ := sm.RegisterNamed(syscall.SIGUSR2, "", func() error { return nil })
+			sm.Unregister(id)
+		}()
+	}
+	wg.Wait()
+}
+
+func Test_SetConcurrency(t *testing.T) {
+	sm := New()
+	sm.SetConcurrency(2)
+
+	var running, maxRunning int32
+	block := make(chan struct{})
+	for i := 0; i < 5; i++ {
+		sm.Register(os.Interrupt, func() error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				if m := atomic.LoadInt32(&maxRunning); n > m {
+					if atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+					continue
+				}
+				break
+			}
+			<-block
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+	}
+
+	sm.handleSignal(os.Interrupt)
+
+	// Wait for the pool to saturate at the configured concurrency before
+	// releasing the handlers, rather than guessing with a sleep.
+	for atomic.LoadInt32(&running) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(block)
+	sm.Wait()
+
+	if maxRunning > 2 {
+		t.Fatalf("expected at most %v concurrent handlers\ngot %v", 2, maxRunning)
+	}
+}
+
+func Test_Channel(t *testing.T) {
+	sm := New()
+
+	ch := sm.Channel(os.Interrupt, 1)
+	sm.handleSignal(os.Interrupt)
+
+	select {
+	case sig := <-ch:
+		if sig != os.Interrupt {
+			t.Fatalf("expected %v\ngot %v", os.Interrupt, sig)
+		}
+	default:
+		t.Fatal("expected a signal on the channel")
+	}
+}
+
+func Test_ChannelAll(t *testing.T) {
+	sm := New()
+
+	ch := sm.ChannelAll(1)
+	sm.handleSignal(syscall.SIGTERM)
+
+	select {
+	case sig := <-ch:
+		if sig != syscall.SIGTERM {
+			t.Fatalf("expected %v\ngot %v", syscall.SIGTERM, sig)
+		}
+	default:
+		t.Fatal("expected a signal on the channel")
+	}
+}
+
+func Test_Channel_nonblocking_drop(t *testing.T) {
+	sm := New()
+	ec := make(chan error, 1)
+	sm.SetErrChannel(ec)
+
+	ch := sm.Channel(os.Interrupt, 1)
+	sm.handleSignal(os.Interrupt) // fills the buffer
+	sm.handleSignal(os.Interrupt) // should be dropped, not block
+
+	err := <-ec
+	if err == nil {
+		t.Fatal("expected an error reporting the dropped signal")
+	}
+
+	if len(ch) != 1 {
+		t.Fatalf("expected %v buffered signal\ngot %v", 1, len(ch))
+	}
+}
+
+// Test_Channel_nonblocking_drop_errChannel_full guards against
+// reporting a dropped signal on ec blocking the caller itself: if ec's
+// buffer is already full, handleSignal must not hang waiting for it.
+func Test_Channel_nonblocking_drop_errChannel_full(t *testing.T) {
+	sm := New()
+	ec := make(chan error, 1)
+	ec <- fmt.Errorf("pre-existing error occupying the buffer")
+	sm.SetErrChannel(ec)
+
+	sm.Channel(os.Interrupt, 1)
+	sm.handleSignal(os.Interrupt) // fills the channel subscriber's buffer
+	done := make(chan struct{})
+	go func() {
+		sm.handleSignal(os.Interrupt) // drop + report on an already-full ec
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handleSignal blocked reporting a dropped signal on a full error channel")
+	}
+}
+
+func Test_handleSignal_channel_only_no_error(t *testing.T) {
+	sm := New()
+	ec := make(chan error, 1)
+	sm.SetErrChannel(ec)
+
+	sm.Channel(os.Interrupt, 1)
+	sm.handleSignal(os.Interrupt)
+
+	if len(ec) != 0 {
+		t.Fatalf("expected no error for a signal with only a channel subscriber\ngot %v", <-ec)
+	}
+}
+
+func Test_StopChannel(t *testing.T) {
+	sm := New()
+
+	ch := sm.Channel(os.Interrupt, 1)
+	sm.StopChannel(ch)
+
+	if subs := sm.chanSubs[os.Interrupt]; len(subs) != 0 {
+		t.Fatalf("expected no remaining subscribers\ngot %v", subs)
+	}
+
+	// Signal delivery was handed back to the OS's default disposition,
+	// so the Signalman no longer has anything registered for it.
+	ec := make(chan error, 1)
+	sm.SetErrChannel(ec)
+	sm.handleSignal(os.Interrupt)
+	if err := <-ec; err == nil {
+		t.Fatal("expected an error, since no handlers remain for the signal")
+	}
+}
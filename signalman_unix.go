@@ -0,0 +1,15 @@
+//go:build !windows
+// +build !windows
+
+package signalman
+
+import "syscall"
+
+// sigHUP, sigUSR1, sigUSR2 and sigQUIT back OnReload, OnToggleDebug and
+// OnDump on platforms that actually support these signals.
+const (
+	sigHUP  = syscall.SIGHUP
+	sigUSR1 = syscall.SIGUSR1
+	sigUSR2 = syscall.SIGUSR2
+	sigQUIT = syscall.SIGQUIT
+)
@@ -0,0 +1,23 @@
+//go:build windows
+// +build windows
+
+package signalman
+
+import "os"
+
+// windowsSignal is a placeholder os.Signal for signals that don't exist
+// on Windows. The OS will never actually send one, so registering
+// against it is effectively a no-op.
+type windowsSignal string
+
+func (s windowsSignal) String() string { return string(s) }
+func (s windowsSignal) Signal()        {}
+
+// sigHUP, sigUSR1, sigUSR2 and sigQUIT have no Windows equivalent, so
+// OnReload, OnToggleDebug and OnDump fall back to no-ops there.
+var (
+	sigHUP  os.Signal = windowsSignal("SIGHUP")
+	sigUSR1 os.Signal = windowsSignal("SIGUSR1")
+	sigUSR2 os.Signal = windowsSignal("SIGUSR2")
+	sigQUIT os.Signal = windowsSignal("SIGQUIT")
+)